@@ -0,0 +1,213 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const poolStatsInterval = 15 * time.Second
+
+type observabilityOptions struct {
+	registerer     prometheus.Registerer
+	tracerProvider trace.TracerProvider
+}
+
+var obsOpts = observabilityOptions{
+	registerer:     prometheus.DefaultRegisterer,
+	tracerProvider: otel.GetTracerProvider(),
+}
+
+// Option configures the package-wide observability layer applied to every
+// client newRedis builds. Unset options default to Prometheus's default
+// registerer and OpenTelemetry's global tracer provider, so instrumentation
+// works out of the box with zero configuration.
+type Option func(*observabilityOptions)
+
+// WithMetricsRegistry registers the command/pool metrics on r instead of the
+// default Prometheus registerer.
+func WithMetricsRegistry(r *prometheus.Registry) Option {
+	return func(o *observabilityOptions) { o.registerer = r }
+}
+
+// WithTracerProvider uses tp for command and pipeline spans instead of the
+// global OpenTelemetry tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *observabilityOptions) { o.tracerProvider = tp }
+}
+
+// Configure applies opts to the package-wide observability configuration.
+// Call it once at startup, before Connect, so every client picks it up.
+func Configure(opts ...Option) {
+	for _, opt := range opts {
+		opt(&obsOpts)
+	}
+	registerMetrics(obsOpts.registerer)
+}
+
+var (
+	commandsTotal   *prometheus.CounterVec
+	commandErrors   *prometheus.CounterVec
+	commandDuration *prometheus.HistogramVec
+	poolHits        *prometheus.GaugeVec
+	poolMisses      *prometheus.GaugeVec
+	poolTimeouts    *prometheus.GaugeVec
+	poolTotalConns  *prometheus.GaugeVec
+	poolIdleConns   *prometheus.GaugeVec
+
+	metricsOnce sync.Once
+)
+
+// registerMetrics builds and registers the metric vars against reg the first
+// time it's called; later calls (whether from Configure or from the first
+// client built by newRedis) are no-ops. Metrics are never registered eagerly
+// at package-init time so a Configure(WithMetricsRegistry(...)) call made
+// before the first Connect still takes effect.
+func registerMetrics(reg prometheus.Registerer) {
+	metricsOnce.Do(func() {
+		commandsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redis_commands_total",
+			Help: "Total number of redis commands executed, by client and command name.",
+		}, []string{"client", "command"})
+
+		commandErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redis_command_errors_total",
+			Help: "Total number of redis commands that returned an error, by client and command name.",
+		}, []string{"client", "command"})
+
+		commandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "redis_command_duration_seconds",
+			Help: "Redis command/pipeline latency in seconds, by client and command name.",
+		}, []string{"client", "command"})
+
+		poolHits = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "redis_pool_hits", Help: "Number of pooled connections reused, by client.",
+		}, []string{"client"})
+		poolMisses = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "redis_pool_misses", Help: "Number of new connections created, by client.",
+		}, []string{"client"})
+		poolTimeouts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "redis_pool_timeouts", Help: "Number of times a connection wait timed out, by client.",
+		}, []string{"client"})
+		poolTotalConns = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "redis_pool_total_conns", Help: "Number of connections currently open, by client.",
+		}, []string{"client"})
+		poolIdleConns = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "redis_pool_idle_conns", Help: "Number of idle connections currently in the pool, by client.",
+		}, []string{"client"})
+
+		reg.MustRegister(
+			commandsTotal, commandErrors, commandDuration,
+			poolHits, poolMisses, poolTimeouts, poolTotalConns, poolIdleConns,
+		)
+	})
+}
+
+// observabilityHook emits Prometheus metrics and OpenTelemetry spans for
+// every command/pipeline a client runs, and logs commands exceeding
+// slowThreshold. It is attached to every client newRedis builds.
+type observabilityHook struct {
+	clientName    string
+	slowThreshold time.Duration
+	tracer        trace.Tracer
+}
+
+func newObservabilityHook(clientName string, slowThreshold time.Duration) *observabilityHook {
+	registerMetrics(obsOpts.registerer)
+
+	return &observabilityHook{
+		clientName:    clientName,
+		slowThreshold: slowThreshold,
+		tracer:        obsOpts.tracerProvider.Tracer("redis"),
+	}
+}
+
+func (h *observabilityHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *observabilityHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := h.tracer.Start(ctx, "redis."+cmd.Name(), trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation", cmd.Name()),
+		))
+		start := time.Now()
+		err := next(ctx, cmd)
+		dur := time.Since(start)
+		span.End()
+
+		h.observe(cmd.Name(), len(cmd.Args()), dur, err)
+		return err
+	}
+}
+
+func (h *observabilityHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := h.tracer.Start(ctx, "redis.pipeline", trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.Int("db.redis.num_cmd", len(cmds)),
+		))
+		start := time.Now()
+		err := next(ctx, cmds)
+		dur := time.Since(start)
+		span.End()
+
+		for _, cmd := range cmds {
+			h.observe(cmd.Name(), len(cmd.Args()), 0, cmd.Err())
+		}
+		commandDuration.WithLabelValues(h.clientName, "pipeline").Observe(dur.Seconds())
+
+		if h.slowThreshold > 0 && dur >= h.slowThreshold {
+			logger.Printf("[redis] slow pipeline: client=%s cmds=%d duration=%s", h.clientName, len(cmds), dur)
+		}
+
+		return err
+	}
+}
+
+func (h *observabilityHook) observe(cmdName string, argCount int, dur time.Duration, err error) {
+	commandsTotal.WithLabelValues(h.clientName, cmdName).Inc()
+	if err != nil && err != redis.Nil {
+		commandErrors.WithLabelValues(h.clientName, cmdName).Inc()
+	}
+
+	if dur > 0 {
+		commandDuration.WithLabelValues(h.clientName, cmdName).Observe(dur.Seconds())
+	}
+
+	if h.slowThreshold > 0 && dur >= h.slowThreshold {
+		logger.Printf("[redis] slow command: client=%s cmd=%s args=%d duration=%s", h.clientName, cmdName, argCount, dur)
+	}
+}
+
+// startPoolStatsLoop periodically scrapes client.PoolStats() into gauges
+// until stop is closed. The caller owns stop and must close it once client
+// is no longer in use, or the goroutine leaks.
+func startPoolStatsLoop(clientName string, client redis.UniversalClient, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(poolStatsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				stats := client.PoolStats()
+				poolHits.WithLabelValues(clientName).Set(float64(stats.Hits))
+				poolMisses.WithLabelValues(clientName).Set(float64(stats.Misses))
+				poolTimeouts.WithLabelValues(clientName).Set(float64(stats.Timeouts))
+				poolTotalConns.WithLabelValues(clientName).Set(float64(stats.TotalConns))
+				poolIdleConns.WithLabelValues(clientName).Set(float64(stats.IdleConns))
+			}
+		}
+	}()
+}
@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestConfigureRegistersMetricsOnConfiguredRegistry pins down the chunk0-8
+// fix: a Configure(WithMetricsRegistry(...)) call made before the first
+// client is built must register metrics on that registry, not silently keep
+// using prometheus.DefaultRegisterer from an eager package-init registration.
+// This must run before anything else in the package triggers registerMetrics
+// (metricsOnce only fires once), so it lives in a file that sorts ahead of
+// redis_test.go.
+func TestConfigureRegistersMetricsOnConfiguredRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	Configure(WithMetricsRegistry(reg))
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+
+	found := false
+	for _, mf := range mfs {
+		if mf.GetName() == "redis_commands_total" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("expected redis_commands_total to be registered on the configured registry, got %d metric families", len(mfs))
+	}
+}
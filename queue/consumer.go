@@ -0,0 +1,305 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultConcurrency       = 1
+	defaultMaxAttempts       = 5
+	defaultVisibilityTimeout = 30 * time.Second
+	defaultPollInterval      = 5 * time.Second
+	reclaimBatchSize         = 100
+	deadlineAddRetries       = 3
+	workerErrorBackoff       = time.Second
+)
+
+// HandlerFunc processes a single task. A returned error causes the task to
+// be retried (with backoff) until MaxAttempts is reached, after which it is
+// moved to the dead-letter list.
+type HandlerFunc func(ctx context.Context, task *Task) error
+
+type consumerOptions struct {
+	concurrency       int
+	maxAttempts       int
+	backoff           func(attempts int) time.Duration
+	visibilityTimeout time.Duration
+	pollInterval      time.Duration
+}
+
+// ConsumerOption configures a Consumer.
+type ConsumerOption func(*consumerOptions)
+
+// WithConcurrency sets how many tasks are processed in parallel. Default 1.
+func WithConcurrency(n int) ConsumerOption {
+	return func(o *consumerOptions) { o.concurrency = n }
+}
+
+// WithMaxAttempts sets how many attempts a task gets before it is
+// dead-lettered. Default 5.
+func WithMaxAttempts(n int) ConsumerOption {
+	return func(o *consumerOptions) { o.maxAttempts = n }
+}
+
+// WithBackoff overrides the default exponential backoff (1s * 2^attempts,
+// capped at 5m) used between retries.
+func WithBackoff(f func(attempts int) time.Duration) ConsumerOption {
+	return func(o *consumerOptions) { o.backoff = f }
+}
+
+// WithVisibilityTimeout sets how long a task may stay in-flight before the
+// reclaim loop assumes its consumer died and retries it. Default 30s.
+func WithVisibilityTimeout(d time.Duration) ConsumerOption {
+	return func(o *consumerOptions) { o.visibilityTimeout = d }
+}
+
+func defaultBackoff(attempts int) time.Duration {
+	d := time.Second << uint(attempts)
+	// Past 30 doublings the shift overflows int64, so cap before trusting d.
+	if attempts > 30 || d > 5*time.Minute {
+		d = 5 * time.Minute
+	}
+	return d
+}
+
+// Consumer pulls tasks off a named queue and runs them through a HandlerFunc.
+type Consumer struct {
+	client  redis.UniversalClient
+	name    string
+	handler HandlerFunc
+	opts    consumerOptions
+}
+
+// NewConsumer returns a Consumer that processes tasks from the named queue
+// with handler, tuned by opts.
+func NewConsumer(client redis.UniversalClient, name string, handler HandlerFunc, opts ...ConsumerOption) *Consumer {
+	o := consumerOptions{
+		concurrency:       defaultConcurrency,
+		maxAttempts:       defaultMaxAttempts,
+		backoff:           defaultBackoff,
+		visibilityTimeout: defaultVisibilityTimeout,
+		pollInterval:      defaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	registerMetrics(obsOpts.registerer)
+
+	return &Consumer{client: client, name: name, handler: handler, opts: o}
+}
+
+// Run starts the worker pool, the delayed-task mover, and the in-flight
+// reclaim loop. It blocks until ctx is canceled.
+func (c *Consumer) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		c.moveDelayedLoop(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		c.reclaimLoop(ctx)
+	}()
+
+	for i := 0; i < c.opts.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.workerLoop(ctx)
+		}()
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (c *Consumer) workerLoop(ctx context.Context) {
+	for ctx.Err() == nil {
+		id, err := c.client.BRPopLPush(ctx, readyKey(c.name), inflightKey(c.name), c.opts.pollInterval).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.sleep(ctx, workerErrorBackoff)
+			continue
+		}
+
+		if err := c.trackInFlight(ctx, id); err != nil {
+			continue
+		}
+
+		c.process(ctx, id)
+	}
+}
+
+// trackInFlight records id's visibility-timeout deadline. BRPopLPush has
+// already moved id into the in-flight list by the time this runs, so a
+// failure here can't just be swallowed: it retries a few times and, if the
+// zset write still won't go through, undoes the move by pushing id back onto
+// readyKey. Otherwise id would sit in inflightKey with no deadline entry and
+// reclaimOnce would never find it to requeue.
+func (c *Consumer) trackInFlight(ctx context.Context, id string) error {
+	deadline := time.Now().Add(c.opts.visibilityTimeout).UnixMilli()
+
+	var err error
+	for attempt := 0; attempt < deadlineAddRetries; attempt++ {
+		err = c.client.ZAdd(ctx, deadlineKey(c.name), redis.Z{Score: float64(deadline), Member: id}).Err()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// ctx may already be canceled here, so the undo runs on its own context.
+	undoCtx := context.Background()
+	pipe := c.client.TxPipeline()
+	pipe.LRem(undoCtx, inflightKey(c.name), 1, id)
+	pipe.LPush(undoCtx, readyKey(c.name), id)
+	pipe.Exec(undoCtx)
+
+	return err
+}
+
+// sleep blocks for d or until ctx is canceled, whichever comes first.
+func (c *Consumer) sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
+func (c *Consumer) process(ctx context.Context, id string) {
+	envelope, err := c.client.HGet(ctx, tasksKey(c.name), id).Result()
+	if err != nil {
+		return
+	}
+
+	var task Task
+	if err := json.Unmarshal([]byte(envelope), &task); err != nil {
+		return
+	}
+
+	err = c.handler(ctx, &task)
+
+	pipe := c.client.TxPipeline()
+	pipe.LRem(ctx, inflightKey(c.name), 1, id)
+	pipe.ZRem(ctx, deadlineKey(c.name), id)
+
+	if err == nil {
+		pipe.HDel(ctx, tasksKey(c.name), id)
+		if _, execErr := pipe.Exec(ctx); execErr == nil {
+			processedTotal.WithLabelValues(c.name, task.Type, "success").Inc()
+		}
+		return
+	}
+
+	task.Attempts++
+	if task.Attempts >= c.opts.maxAttempts {
+		pipe.RPush(ctx, deadLetterKey(c.name), id)
+		if _, execErr := pipe.Exec(ctx); execErr == nil {
+			deadLetteredTotal.WithLabelValues(c.name, task.Type).Inc()
+			processedTotal.WithLabelValues(c.name, task.Type, "dead").Inc()
+		}
+		return
+	}
+
+	task.NotBefore = time.Now().Add(c.opts.backoff(task.Attempts)).UnixMilli()
+	if body, marshalErr := json.Marshal(task); marshalErr == nil {
+		pipe.HSet(ctx, tasksKey(c.name), id, body)
+	}
+	pipe.ZAdd(ctx, delayedKey(c.name), redis.Z{Score: float64(task.NotBefore), Member: id})
+	if _, execErr := pipe.Exec(ctx); execErr == nil {
+		processedTotal.WithLabelValues(c.name, task.Type, "retry").Inc()
+	}
+}
+
+// moveDelayedLoop promotes due delayed tasks onto the ready list every tick.
+func (c *Consumer) moveDelayedLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := promoteScript.Run(ctx, c.client,
+				[]string{delayedKey(c.name), readyKey(c.name)},
+				time.Now().UnixMilli(), reclaimBatchSize,
+			).Result()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				continue
+			}
+		}
+	}
+}
+
+// reclaimLoop moves tasks whose visibility timeout has elapsed back onto the
+// ready list for a worker to pick up via BRPopLPush, so a dead consumer
+// doesn't strand them.
+func (c *Consumer) reclaimLoop(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.visibilityTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.reclaimOnce(ctx)
+		}
+	}
+}
+
+// reclaimOnce requeues tasks whose visibility timeout has elapsed back onto
+// readyKey rather than invoking the handler itself, so a still-alive but
+// slow worker doesn't race with reclaimLoop over the same task id, and
+// reclaimed tasks go back through the normal worker pool (and WithConcurrency)
+// instead of running serially inside this loop.
+func (c *Consumer) reclaimOnce(ctx context.Context) {
+	now := time.Now().UnixMilli()
+	ids, err := c.client.ZRangeByScore(ctx, deadlineKey(c.name), &redis.ZRangeBy{
+		Min: "-inf", Max: fmt.Sprintf("%d", now), Offset: 0, Count: reclaimBatchSize,
+	}).Result()
+	if err != nil || len(ids) == 0 {
+		return
+	}
+
+	for _, id := range ids {
+		pipe := c.client.TxPipeline()
+		pipe.ZRem(ctx, deadlineKey(c.name), id)
+		pipe.LRem(ctx, inflightKey(c.name), 1, id)
+		pipe.LPush(ctx, readyKey(c.name), id)
+		// Exec is atomic: a failure leaves id in the deadline zset, so the
+		// next reclaimLoop tick retries it.
+		pipe.Exec(ctx)
+	}
+}
+
+// promoteScript atomically moves due members of a delayed zset onto a ready
+// list. KEYS: [delayed, ready]. ARGV: [nowMs, limit].
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, id in ipairs(due) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('LPUSH', KEYS[2], id)
+end
+return #due
+`)
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// seedTask stores task's envelope in tasksKey and places its id in
+// inflightKey, mirroring the state a BRPopLPush leaves for process/reclaimOnce
+// to act on, without needing a real worker loop running.
+func seedTask(t *testing.T, ctx context.Context, client redis.UniversalClient, name string, task Task) {
+	t.Helper()
+
+	envelope, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("marshal task: %v", err)
+	}
+	if err := client.HSet(ctx, tasksKey(name), task.ID, envelope).Err(); err != nil {
+		t.Fatalf("HSet: %v", err)
+	}
+	if err := client.LPush(ctx, inflightKey(name), task.ID).Err(); err != nil {
+		t.Fatalf("LPush inflight: %v", err)
+	}
+}
+
+func TestConsumerProcessRetriesOnHandlerError(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	task := Task{ID: "task-1", Type: "created", Payload: json.RawMessage(`"payload"`)}
+	seedTask(t, ctx, client, "orders", task)
+	if err := client.ZAdd(ctx, deadlineKey("orders"), redis.Z{Score: float64(time.Now().UnixMilli()), Member: task.ID}).Err(); err != nil {
+		t.Fatalf("ZAdd: %v", err)
+	}
+
+	c := NewConsumer(client, "orders", func(ctx context.Context, task *Task) error {
+		return errors.New("boom")
+	}, WithMaxAttempts(5))
+
+	c.process(ctx, task.ID)
+
+	if n, _ := client.LLen(ctx, inflightKey("orders")).Result(); n != 0 {
+		t.Fatalf("expected task removed from inflightKey, len=%d", n)
+	}
+	if n, _ := client.ZCard(ctx, deadlineKey("orders")).Result(); n != 0 {
+		t.Fatalf("expected task removed from deadlineKey, len=%d", n)
+	}
+
+	members, err := client.ZRange(ctx, delayedKey("orders"), 0, -1).Result()
+	if err != nil || len(members) != 1 || members[0] != task.ID {
+		t.Fatalf("delayedKey = %v, err=%v, want [%s]", members, err, task.ID)
+	}
+
+	envelope, err := client.HGet(ctx, tasksKey("orders"), task.ID).Result()
+	if err != nil {
+		t.Fatalf("HGet: %v", err)
+	}
+	var stored Task
+	if err := json.Unmarshal([]byte(envelope), &stored); err != nil {
+		t.Fatalf("unmarshal stored task: %v", err)
+	}
+	if stored.Attempts != 1 {
+		t.Fatalf("stored task Attempts = %d, want 1", stored.Attempts)
+	}
+}
+
+func TestConsumerProcessDeadLettersAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	task := Task{ID: "task-1", Type: "created", Payload: json.RawMessage(`"payload"`), Attempts: 2}
+	seedTask(t, ctx, client, "orders", task)
+
+	c := NewConsumer(client, "orders", func(ctx context.Context, task *Task) error {
+		return errors.New("boom")
+	}, WithMaxAttempts(3))
+
+	c.process(ctx, task.ID)
+
+	ids, err := client.LRange(ctx, deadLetterKey("orders"), 0, -1).Result()
+	if err != nil || len(ids) != 1 || ids[0] != task.ID {
+		t.Fatalf("deadLetterKey = %v, err=%v, want [%s]", ids, err, task.ID)
+	}
+}
+
+func TestConsumerProcessSucceedsAndCleansUp(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	task := Task{ID: "task-1", Type: "created", Payload: json.RawMessage(`"payload"`)}
+	seedTask(t, ctx, client, "orders", task)
+
+	c := NewConsumer(client, "orders", func(ctx context.Context, task *Task) error {
+		return nil
+	})
+
+	c.process(ctx, task.ID)
+
+	if exists, err := client.HExists(ctx, tasksKey("orders"), task.ID).Result(); err != nil || exists {
+		t.Fatalf("expected task envelope removed on success, exists=%v err=%v", exists, err)
+	}
+}
+
+func TestReclaimOnceRequeuesExpiredInFlightTasks(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	id := "task-1"
+	if err := client.LPush(ctx, inflightKey("orders"), id).Err(); err != nil {
+		t.Fatalf("LPush inflight: %v", err)
+	}
+	past := time.Now().Add(-time.Minute).UnixMilli()
+	if err := client.ZAdd(ctx, deadlineKey("orders"), redis.Z{Score: float64(past), Member: id}).Err(); err != nil {
+		t.Fatalf("ZAdd: %v", err)
+	}
+
+	c := NewConsumer(client, "orders", func(ctx context.Context, task *Task) error { return nil })
+	c.reclaimOnce(ctx)
+
+	ready, err := client.LRange(ctx, readyKey("orders"), 0, -1).Result()
+	if err != nil || len(ready) != 1 || ready[0] != id {
+		t.Fatalf("readyKey = %v, err=%v, want [%s]", ready, err, id)
+	}
+	if n, _ := client.LLen(ctx, inflightKey("orders")).Result(); n != 0 {
+		t.Fatalf("expected task removed from inflightKey, len=%d", n)
+	}
+	if n, _ := client.ZCard(ctx, deadlineKey("orders")).Result(); n != 0 {
+		t.Fatalf("expected task removed from deadlineKey, len=%d", n)
+	}
+}
@@ -0,0 +1,71 @@
+package queue
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type observabilityOptions struct {
+	registerer prometheus.Registerer
+}
+
+var obsOpts = observabilityOptions{
+	registerer: prometheus.DefaultRegisterer,
+}
+
+// Option configures the package-wide observability layer applied to every
+// Producer/Consumer this package builds. Unset options default to
+// Prometheus's default registerer, so instrumentation works out of the box
+// with zero configuration.
+type Option func(*observabilityOptions)
+
+// WithMetricsRegistry registers queue metrics on r instead of the default
+// Prometheus registerer.
+func WithMetricsRegistry(r prometheus.Registerer) Option {
+	return func(o *observabilityOptions) { o.registerer = r }
+}
+
+// Configure applies opts to the package-wide observability configuration.
+// Call it once at startup, before the first NewProducer/NewConsumer, so
+// metrics register against the intended registry.
+func Configure(opts ...Option) {
+	for _, opt := range opts {
+		opt(&obsOpts)
+	}
+	registerMetrics(obsOpts.registerer)
+}
+
+var (
+	enqueuedTotal     *prometheus.CounterVec
+	processedTotal    *prometheus.CounterVec
+	deadLetteredTotal *prometheus.CounterVec
+
+	metricsOnce sync.Once
+)
+
+// registerMetrics builds and registers the metric vars against reg the first
+// time it's called; later calls (whether from Configure or from the first
+// Producer/Consumer built) are no-ops. Metrics are never registered eagerly
+// at package-init time so a Configure(WithMetricsRegistry(...)) call made
+// before the first NewProducer/NewConsumer still takes effect.
+func registerMetrics(reg prometheus.Registerer) {
+	metricsOnce.Do(func() {
+		enqueuedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redis_queue_enqueued_total",
+			Help: "Total number of tasks enqueued, by queue and task type.",
+		}, []string{"queue", "type"})
+
+		processedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redis_queue_processed_total",
+			Help: "Total number of tasks processed, by queue, task type and outcome (success/retry/dead).",
+		}, []string{"queue", "type", "outcome"})
+
+		deadLetteredTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redis_queue_dead_lettered_total",
+			Help: "Total number of tasks moved to the dead-letter list after exhausting their retries.",
+		}, []string{"queue", "type"})
+
+		reg.MustRegister(enqueuedTotal, processedTotal, deadLetteredTotal)
+	})
+}
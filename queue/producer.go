@@ -0,0 +1,113 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultUniqueTTL = time.Hour
+
+type enqueueOptions struct {
+	delay     time.Duration
+	uniqueKey string
+	uniqueTTL time.Duration
+}
+
+// EnqueueOption configures a single Producer.Enqueue call.
+type EnqueueOption func(*enqueueOptions)
+
+// WithDelay schedules the task to become ready no sooner than d from now.
+func WithDelay(d time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) { o.delay = d }
+}
+
+// WithUnique rejects the enqueue with ErrDuplicateTask if a task with the
+// same key was enqueued less than ttl ago. A zero ttl falls back to 1h.
+func WithUnique(key string, ttl time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) { o.uniqueKey = key; o.uniqueTTL = ttl }
+}
+
+// Producer enqueues tasks for a single named queue.
+type Producer struct {
+	client redis.UniversalClient
+	name   string
+}
+
+// NewProducer returns a Producer that enqueues onto the named queue using client.
+func NewProducer(client redis.UniversalClient, name string) *Producer {
+	registerMetrics(obsOpts.registerer)
+	return &Producer{client: client, name: name}
+}
+
+// Enqueue stores payload as a task of the given type and makes it ready for a
+// Consumer to pick up, subject to WithDelay/WithUnique. It returns the task id.
+func (p *Producer) Enqueue(ctx context.Context, taskType string, payload interface{}, opts ...EnqueueOption) (string, error) {
+	var o enqueueOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.uniqueKey != "" {
+		ttl := o.uniqueTTL
+		if ttl <= 0 {
+			ttl = defaultUniqueTTL
+		}
+
+		ok, err := p.client.SetNX(ctx, uniqueKey(p.name, o.uniqueKey), 1, ttl).Result()
+		if err != nil {
+			return "", fmt.Errorf("queue: check unique key: %w", err)
+		}
+		if !ok {
+			return "", ErrDuplicateTask
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("queue: marshal payload: %w", err)
+	}
+
+	id, err := newTaskID()
+	if err != nil {
+		return "", fmt.Errorf("queue: generate task id: %w", err)
+	}
+
+	var notBefore int64
+	if o.delay > 0 {
+		notBefore = time.Now().Add(o.delay).UnixMilli()
+	}
+
+	task := Task{ID: id, Type: taskType, Payload: body, NotBefore: notBefore}
+	envelope, err := json.Marshal(task)
+	if err != nil {
+		return "", fmt.Errorf("queue: marshal task: %w", err)
+	}
+
+	pipe := p.client.TxPipeline()
+	pipe.HSet(ctx, tasksKey(p.name), id, envelope)
+	if notBefore > 0 {
+		pipe.ZAdd(ctx, delayedKey(p.name), redis.Z{Score: float64(notBefore), Member: id})
+	} else {
+		pipe.LPush(ctx, readyKey(p.name), id)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("queue: enqueue: %w", err)
+	}
+
+	enqueuedTotal.WithLabelValues(p.name, taskType).Inc()
+	return id, nil
+}
+
+func newTaskID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
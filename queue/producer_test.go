@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestClient(t *testing.T) redis.UniversalClient {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	return redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
+
+func TestProducerEnqueuePushesOntoReadyList(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	p := NewProducer(client, "orders")
+	id, err := p.Enqueue(ctx, "created", map[string]string{"order": "1"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	ids, err := client.LRange(ctx, readyKey("orders"), 0, -1).Result()
+	if err != nil {
+		t.Fatalf("LRange: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != id {
+		t.Fatalf("readyKey = %v, want [%s]", ids, id)
+	}
+
+	if exists, err := client.HExists(ctx, tasksKey("orders"), id).Result(); err != nil || !exists {
+		t.Fatalf("expected task envelope in tasksKey, exists=%v err=%v", exists, err)
+	}
+}
+
+func TestProducerEnqueueWithDelaySchedulesOnDelayedZSet(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	p := NewProducer(client, "orders")
+	id, err := p.Enqueue(ctx, "created", "payload", WithDelay(time.Minute))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	if n, err := client.LLen(ctx, readyKey("orders")).Result(); err != nil || n != 0 {
+		t.Fatalf("expected a delayed task to skip readyKey, len=%d err=%v", n, err)
+	}
+
+	members, err := client.ZRange(ctx, delayedKey("orders"), 0, -1).Result()
+	if err != nil {
+		t.Fatalf("ZRange: %v", err)
+	}
+	if len(members) != 1 || members[0] != id {
+		t.Fatalf("delayedKey = %v, want [%s]", members, id)
+	}
+}
+
+func TestProducerEnqueueWithUniqueRejectsDuplicate(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	p := NewProducer(client, "orders")
+	if _, err := p.Enqueue(ctx, "created", "payload", WithUnique("order-1", time.Minute)); err != nil {
+		t.Fatalf("first Enqueue: %v", err)
+	}
+
+	_, err := p.Enqueue(ctx, "created", "payload", WithUnique("order-1", time.Minute))
+	if !errors.Is(err, ErrDuplicateTask) {
+		t.Fatalf("second Enqueue error = %v, want ErrDuplicateTask", err)
+	}
+}
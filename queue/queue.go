@@ -0,0 +1,44 @@
+// Package queue implements a reliable FIFO task queue on top of the redis
+// clients managed by this module's Connect/Manager. Tasks are JSON-encoded
+// envelopes stored in a hash keyed by task id; a ready list and a delayed
+// zset (scored by unix-ms) feed a BRPOPLPUSH-based in-flight list so that a
+// consumer crash doesn't lose a task mid-processing.
+//
+// All keys for a queue share the "{name}" hash tag so a cluster-backed
+// client keeps them on a single slot.
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateTask is returned by Producer.Enqueue when WithUnique rejects a
+// task because a task with the same unique key was enqueued within that key's
+// ttl, whether or not that task has since been processed.
+var ErrDuplicateTask = errors.New("queue: duplicate task")
+
+// Task is the envelope stored in the tasks hash and passed to a HandlerFunc.
+type Task struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	NotBefore int64           `json:"not_before"` // unix-ms
+}
+
+// Unmarshal decodes the task payload into v.
+func (t *Task) Unmarshal(v interface{}) error {
+	return json.Unmarshal(t.Payload, v)
+}
+
+func readyKey(name string) string      { return fmt.Sprintf("queue:{%s}:ready", name) }
+func delayedKey(name string) string    { return fmt.Sprintf("queue:{%s}:delayed", name) }
+func inflightKey(name string) string   { return fmt.Sprintf("queue:{%s}:inflight", name) }
+func deadlineKey(name string) string   { return fmt.Sprintf("queue:{%s}:deadlines", name) }
+func tasksKey(name string) string      { return fmt.Sprintf("queue:{%s}:tasks", name) }
+func deadLetterKey(name string) string { return fmt.Sprintf("queue:{%s}:dead", name) }
+func uniqueKey(name, key string) string {
+	return fmt.Sprintf("queue:{%s}:unique:%s", name, key)
+}